@@ -0,0 +1,91 @@
+// Command kexamples-exec watches for CrashLoopBackOff containers using
+// pkg/crashwatch and, for each one, execs into a debug sidecar to capture
+// diagnostics -- the pairing the crashwatch package's doc comment alludes to:
+// "create deployment -> wait -> exec".
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/faizanbashir/k8s-go-sdk-examples/pkg/crashwatch"
+	"github.com/faizanbashir/k8s-go-sdk-examples/pkg/podexec"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// debugSink execs into a debug container whenever a container crash-loops,
+// dumping either /proc/1/status from the debug sidecar or the crashed
+// container's environment, whichever the pod exposes.
+type debugSink struct {
+	config       *rest.Config
+	client       kubernetes.Interface
+	debugSidecar string
+}
+
+func (d *debugSink) Send(event crashwatch.Event) error {
+	ref := podexec.PodRef{Namespace: event.Namespace, Name: event.Pod, Container: d.debugSidecar}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stdout, stderr, err := podexec.Exec(ctx, d.config, d.client, ref, []string{"cat", "/proc/1/status"}, podexec.Options{})
+	if err != nil {
+		fmt.Printf("exec into debug sidecar for %s/%s failed (%v), falling back to env dump\n", event.Namespace, event.Pod, err)
+		ref.Container = event.Container
+		stdout, stderr, err = podexec.Exec(ctx, d.config, d.client, ref, []string{"env"}, podexec.Options{})
+		if err != nil {
+			return fmt.Errorf("error dumping env for %s/%s/%s: %v", event.Namespace, event.Pod, event.Container, err)
+		}
+	}
+
+	fmt.Printf("--- diagnostics for %s/%s/%s (restart #%d) ---\n%s\n", event.Namespace, event.Pod, event.Container, event.RestartCount, bytes.TrimSpace(stdout))
+	if len(stderr) > 0 {
+		fmt.Printf("stderr: %s\n", bytes.TrimSpace(stderr))
+	}
+	return nil
+}
+
+func main() {
+	debugSidecar := flag.String("debug-container", "debug", "name of the debug sidecar container to exec into")
+	flag.Parse()
+
+	userHomeDir, err := os.UserHomeDir()
+	if err != nil {
+		fmt.Printf("error getting user home dir: %v\n", err)
+		os.Exit(1)
+	}
+	kubeConfigPath := filepath.Join(userHomeDir, ".kube", "config")
+	fmt.Printf("Using kubeconfig: %s\n", kubeConfigPath)
+
+	kubeConfig, err := clientcmd.BuildConfigFromFlags("", kubeConfigPath)
+	if err != nil {
+		log.Fatalf("error getting kubernetes config: %v\n", err)
+	}
+
+	client, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		log.Fatalf("error building kubernetes client: %v\n", err)
+	}
+
+	watcher := crashwatch.NewWatcher(client, "", 30*time.Second)
+	watcher.AddEventHandler(crashwatch.StdoutSink{})
+	watcher.AddEventHandler(&debugSink{config: kubeConfig, client: client, debugSidecar: *debugSidecar})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Println("Watching for CrashLoopBackOff containers...")
+	if err := watcher.Start(ctx); err != nil && ctx.Err() == nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,48 @@
+package apply
+
+import (
+	"fmt"
+
+	ocpappsv1client "github.com/openshift/client-go/apps/clientset/versioned/typed/apps/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+// NewClients builds the Kubernetes, OpenShift and dynamic clients, plus a
+// RESTMapper backed by cached discovery, that Apply needs to dispatch
+// manifests of unknown kind. If the cluster has no OpenShift apps API group
+// registered, ocpAppsClient calls inside Apply simply aren't reached because
+// no manifest will resolve to that GroupKind.
+func NewClients(config *rest.Config) (*Clients, error) {
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error building kubernetes client: %v", err)
+	}
+
+	ocpApps, err := ocpappsv1client.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error building openshift apps client: %v", err)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error building dynamic client: %v", err)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error building discovery client: %v", err)
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+
+	return &Clients{
+		Kubernetes:    kubeClient,
+		OpenShiftApps: ocpApps,
+		Dynamic:       dynamicClient,
+		Mapper:        mapper,
+	}, nil
+}
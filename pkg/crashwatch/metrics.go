@@ -0,0 +1,17 @@
+package crashwatch
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// crashLoopEventsTotal counts every CrashLoopBackOff transition the watcher
+// emits to its sinks, labeled so operators can slice by namespace/pod/reason.
+var crashLoopEventsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "crashloop_events_total",
+		Help: "Total number of CrashLoopBackOff events emitted by the crashwatch informer.",
+	},
+	[]string{"namespace", "pod", "reason"},
+)
+
+func init() {
+	prometheus.MustRegister(crashLoopEventsTotal)
+}
@@ -0,0 +1,106 @@
+package crashwatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Sink receives every deduplicated CrashLoopBackOff Event. Implementations
+// must return quickly; slow sinks should buffer or run their own goroutine.
+type Sink interface {
+	Send(event Event) error
+}
+
+// StdoutSink prints events as human-readable lines, mirroring the old
+// watcher's `fmt.Printf` output.
+type StdoutSink struct{}
+
+func (StdoutSink) Send(event Event) error {
+	fmt.Printf("PodName: %s, Namespace: %s, Container: %s, RestartCount: %d, Reason: %s\n",
+		event.Pod, event.Namespace, event.Container, event.RestartCount, event.Reason)
+	return nil
+}
+
+// JSONLinesSink writes one JSON-encoded Event per line to the given writer.
+type JSONLinesSink struct {
+	Writer interface {
+		Write(p []byte) (n int, err error)
+	}
+}
+
+func (s JSONLinesSink) Send(event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error marshaling event: %v", err)
+	}
+	payload = append(payload, '\n')
+	if _, err := s.Writer.Write(payload); err != nil {
+		return fmt.Errorf("error writing event: %v", err)
+	}
+	return nil
+}
+
+// WebhookSink POSTs the JSON-encoded event to an arbitrary HTTP endpoint.
+type WebhookSink struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewWebhookSink returns a WebhookSink with a sane default HTTP timeout.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		URL:    url,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *WebhookSink) Send(event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error marshaling event: %v", err)
+	}
+	resp, err := s.Client.Post(s.URL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("error posting event to webhook: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %s", resp.Status)
+	}
+	return nil
+}
+
+// SlackSink posts a simple text message to a Slack incoming webhook URL.
+type SlackSink struct {
+	WebhookURL string
+	Client     *http.Client
+}
+
+// NewSlackSink returns a SlackSink with a sane default HTTP timeout.
+func NewSlackSink(webhookURL string) *SlackSink {
+	return &SlackSink{
+		WebhookURL: webhookURL,
+		Client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *SlackSink) Send(event Event) error {
+	text := fmt.Sprintf(":rotating_light: `%s/%s` container `%s` is %s (restart #%d)",
+		event.Namespace, event.Pod, event.Container, event.Reason, event.RestartCount)
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("error marshaling slack payload: %v", err)
+	}
+	resp, err := s.Client.Post(s.WebhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("error posting event to slack: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned status %s", resp.Status)
+	}
+	return nil
+}
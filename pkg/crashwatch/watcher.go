@@ -0,0 +1,242 @@
+// Package crashwatch watches Pods across the cluster for containers stuck in
+// CrashLoopBackOff, enriches each transition with the container's last
+// termination log and any correlated Events, and fans the result out to one
+// or more Sinks.
+//
+// It replaces the raw client.CoreV1().Pods("").Watch(...) loop from
+// k8s/pods/watcher: a bare Watch() channel silently drops events when the
+// connection is re-established and has no notion of "already reported", so
+// the same transition gets logged repeatedly after every rewatch. A
+// SharedIndexInformer with a resync period and an explicit dedup key fixes
+// both problems.
+package crashwatch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Event describes a single CrashLoopBackOff transition, enriched with the
+// container's last termination log and correlated cluster Events.
+type Event struct {
+	Namespace       string    `json:"namespace"`
+	Pod             string    `json:"pod"`
+	Container       string    `json:"container"`
+	PodUID          string    `json:"podUID"`
+	RestartCount    int32     `json:"restartCount"`
+	Reason          string    `json:"reason"`
+	ObservedAt      time.Time `json:"observedAt"`
+	PreviousLog     string    `json:"previousLog,omitempty"`
+	CorrelatedNotes []string  `json:"correlatedEvents,omitempty"`
+}
+
+// key uniquely identifies a container's crash transition so resync-driven
+// re-emissions of the same state can be deduplicated.
+type key struct {
+	namespace    string
+	pod          string
+	container    string
+	restartCount int32
+}
+
+// Watcher watches Pods for CrashLoopBackOff containers and notifies Sinks.
+type Watcher struct {
+	client       kubernetes.Interface
+	resync       time.Duration
+	queue        workqueue.RateLimitingInterface
+	informer     cache.SharedIndexInformer
+	sinks        []Sink
+	seen         map[key]struct{}
+	eventLookups func(ctx context.Context, namespace string, podUID string) ([]string, error)
+}
+
+// NewWatcher builds a Watcher over every namespace ("" means all namespaces).
+// resync controls how often the informer replays full state; a non-zero
+// value is what lets already-seen transitions be safely deduplicated rather
+// than relied upon to never repeat.
+func NewWatcher(client kubernetes.Interface, namespace string, resync time.Duration) *Watcher {
+	factory := informers.NewSharedInformerFactoryWithOptions(client, resync,
+		informers.WithNamespace(namespace))
+	informer := factory.Core().V1().Pods().Informer()
+
+	w := &Watcher{
+		client:   client,
+		resync:   resync,
+		queue:    workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		informer: informer,
+		seen:     make(map[key]struct{}),
+	}
+	w.eventLookups = w.correlateEvents
+
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    w.enqueue,
+		UpdateFunc: func(_, newObj interface{}) { w.enqueue(newObj) },
+	})
+
+	return w
+}
+
+// AddEventHandler registers a Sink that every deduplicated Event is sent to,
+// in registration order.
+func (w *Watcher) AddEventHandler(sink Sink) {
+	w.sinks = append(w.sinks, sink)
+}
+
+// Start runs the informer and the event processing loop until ctx is
+// canceled. It blocks until shutdown completes.
+func (w *Watcher) Start(ctx context.Context) error {
+	go w.informer.Run(ctx.Done())
+
+	if !cache.WaitForCacheSync(ctx.Done(), w.informer.HasSynced) {
+		return fmt.Errorf("crashwatch: timed out waiting for informer cache to sync")
+	}
+
+	go wait.Until(func() { w.processNextItem(ctx) }, time.Second, ctx.Done())
+
+	<-ctx.Done()
+	w.queue.ShutDown()
+	return ctx.Err()
+}
+
+func (w *Watcher) enqueue(obj interface{}) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	w.queue.Add(pod.Namespace + "/" + pod.Name)
+}
+
+func (w *Watcher) processNextItem(ctx context.Context) {
+	item, shutdown := w.queue.Get()
+	if shutdown {
+		return
+	}
+	defer w.queue.Done(item)
+
+	namespacedName := item.(string)
+	if err := w.handlePod(ctx, namespacedName); err != nil {
+		fmt.Printf("crashwatch: error handling %s: %v\n", namespacedName, err)
+		w.queue.AddRateLimited(item)
+		return
+	}
+	w.queue.Forget(item)
+}
+
+func (w *Watcher) handlePod(ctx context.Context, namespacedName string) error {
+	obj, exists, err := w.informer.GetStore().GetByKey(namespacedName)
+	if err != nil {
+		return fmt.Errorf("error fetching pod %s from store: %v", namespacedName, err)
+	}
+	if !exists {
+		return nil
+	}
+	pod := obj.(*corev1.Pod)
+
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting == nil || status.State.Waiting.Reason != "CrashLoopBackOff" {
+			continue
+		}
+
+		k := key{
+			namespace:    pod.Namespace,
+			pod:          pod.Name,
+			container:    status.Name,
+			restartCount: status.RestartCount,
+		}
+		if _, alreadySeen := w.seen[k]; alreadySeen {
+			continue
+		}
+		w.seen[k] = struct{}{}
+
+		event := Event{
+			Namespace:    pod.Namespace,
+			Pod:          pod.Name,
+			Container:    status.Name,
+			PodUID:       string(pod.UID),
+			RestartCount: status.RestartCount,
+			Reason:       status.State.Waiting.Reason,
+			ObservedAt:   time.Now(),
+		}
+
+		if log, err := w.previousLog(ctx, pod.Namespace, pod.Name, status.Name); err != nil {
+			fmt.Printf("crashwatch: error fetching previous log for %s/%s/%s: %v\n", pod.Namespace, pod.Name, status.Name, err)
+		} else {
+			event.PreviousLog = log
+		}
+
+		if notes, err := w.eventLookups(ctx, pod.Namespace, string(pod.UID)); err != nil {
+			fmt.Printf("crashwatch: error correlating events for %s/%s: %v\n", pod.Namespace, pod.Name, err)
+		} else {
+			event.CorrelatedNotes = notes
+		}
+
+		crashLoopEventsTotal.WithLabelValues(event.Namespace, event.Pod, event.Reason).Inc()
+
+		for _, sink := range w.sinks {
+			if err := sink.Send(event); err != nil {
+				fmt.Printf("crashwatch: error delivering event to sink: %v\n", err)
+			}
+		}
+	}
+	return nil
+}
+
+// previousLog fetches the tail of the last terminated container's log, the
+// same log a user would reach for with `kubectl logs --previous`.
+func (w *Watcher) previousLog(ctx context.Context, namespace, pod, container string) (string, error) {
+	req := w.client.CoreV1().Pods(namespace).GetLogs(pod, &corev1.PodLogOptions{
+		Container: container,
+		Previous:  true,
+	})
+	stream, err := req.Stream(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	buf := make([]byte, 0, 4096)
+	chunk := make([]byte, 4096)
+	for {
+		n, err := stream.Read(chunk)
+		buf = append(buf, chunk[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return string(buf), err
+		}
+		if len(buf) > 64*1024 {
+			break
+		}
+	}
+	return string(buf), nil
+}
+
+// correlateEvents returns a human-readable summary of recent Events in the
+// namespace whose InvolvedObject.UID matches the pod's UID.
+func (w *Watcher) correlateEvents(ctx context.Context, namespace, podUID string) ([]string, error) {
+	selector := fields.OneTermEqualSelector("involvedObject.uid", podUID).String()
+	events, err := w.client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notes := make([]string, 0, len(events.Items))
+	for _, e := range events.Items {
+		notes = append(notes, fmt.Sprintf("[%s] %s: %s", e.Type, e.Reason, e.Message))
+	}
+	return notes, nil
+}
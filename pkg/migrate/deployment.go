@@ -0,0 +1,231 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/faizanbashir/k8s-go-sdk-examples/pkg/workloads"
+)
+
+// MigrateImmutable safely changes an immutable Deployment field (most
+// commonly spec.selector) by running the mutator against a shadow copy,
+// waiting for it to become ready, deleting the original and recreating it
+// with the mutation applied. Progress is journaled so an interrupted run can
+// be resumed with MigrateResume.
+func MigrateImmutable(ctx context.Context, client kubernetes.Interface, ref workloads.Reference, mutator func(*appsv1.Deployment) *appsv1.Deployment) error {
+	original, err := client.AppsV1().Deployments(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting deployment %s: %v", ref, err)
+	}
+
+	mutated := mutator(original.DeepCopy())
+	desiredSpec, err := json.Marshal(mutated.Spec)
+	if err != nil {
+		return fmt.Errorf("error marshaling mutated spec for %s: %v", ref, err)
+	}
+
+	uid := shortSHA(ref.Namespace, ref.Name)
+	journal := &Journal{
+		UID:                 uid,
+		Kind:                workloads.KindDeployment,
+		Namespace:           ref.Namespace,
+		Name:                ref.Name,
+		ShadowName:          fmt.Sprintf("%s-migrate-%s", ref.Name, uid),
+		Phase:               "",
+		DesiredSpec:         desiredSpec,
+		OriginalLabels:      original.Labels,
+		OriginalAnnotations: original.Annotations,
+		Ref:                 ref,
+	}
+
+	return runDeploymentMigration(ctx, client, journal, original)
+}
+
+// MigrateResumeDeployment continues a Deployment migration from whichever
+// phase its journal last completed.
+func MigrateResumeDeployment(ctx context.Context, client kubernetes.Interface, uid string) error {
+	journal, err := LoadJournal(uid)
+	if err != nil {
+		return err
+	}
+	if journal.Kind != workloads.KindDeployment {
+		return fmt.Errorf("journal %s is for kind %s, not Deployment", uid, journal.Kind)
+	}
+
+	original, err := client.AppsV1().Deployments(journal.Namespace).Get(ctx, journal.Name, metav1.GetOptions{})
+	if err != nil && journal.Phase == "" {
+		return fmt.Errorf("error getting deployment %s/%s: %v", journal.Namespace, journal.Name, err)
+	}
+	return runDeploymentMigration(ctx, client, journal, original)
+}
+
+func runDeploymentMigration(ctx context.Context, client kubernetes.Interface, journal *Journal, original *appsv1.Deployment) error {
+	deployments := client.AppsV1().Deployments(journal.Namespace)
+
+	if journal.Phase == "" {
+		shadow := original.DeepCopy()
+		shadow.ObjectMeta = metav1.ObjectMeta{
+			Name:      journal.ShadowName,
+			Namespace: journal.Namespace,
+			Labels:    original.Labels,
+		}
+		if err := json.Unmarshal(journal.DesiredSpec, &shadow.Spec); err != nil {
+			return fmt.Errorf("error unmarshaling desired spec: %v", err)
+		}
+		// Keep the shadow's pods matched by the original's Service by
+		// retaining the original template labels alongside the mutation.
+		for k, v := range original.Spec.Template.Labels {
+			if shadow.Spec.Template.Labels == nil {
+				shadow.Spec.Template.Labels = map[string]string{}
+			}
+			if _, exists := shadow.Spec.Template.Labels[k]; !exists {
+				shadow.Spec.Template.Labels[k] = v
+			}
+		}
+
+		if _, err := deployments.Create(ctx, shadow, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("error creating shadow deployment %s: %v", journal.ShadowName, err)
+		}
+		fmt.Printf("Created shadow deployment %s for migration %s\n", journal.ShadowName, journal.UID)
+		journal.Phase = PhaseShadowCreated
+		if err := saveJournal(journal); err != nil {
+			return err
+		}
+	}
+
+	if journal.Phase == PhaseShadowCreated {
+		if err := waitDeploymentReady(ctx, client, journal.Namespace, journal.ShadowName); err != nil {
+			return fmt.Errorf("error waiting for shadow deployment %s: %v", journal.ShadowName, err)
+		}
+		journal.Phase = PhaseShadowReady
+		if err := saveJournal(journal); err != nil {
+			return err
+		}
+	}
+
+	if journal.Phase == PhaseShadowReady {
+		if err := deleteAndWaitDeployment(ctx, client, journal.Namespace, journal.Name); err != nil {
+			return fmt.Errorf("error deleting original deployment %s: %v", journal.Name, err)
+		}
+		journal.Phase = PhaseOriginalDeleted
+		if err := saveJournal(journal); err != nil {
+			return err
+		}
+	}
+
+	if journal.Phase == PhaseOriginalDeleted {
+		recreated := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        journal.Name,
+				Namespace:   journal.Namespace,
+				Labels:      journal.OriginalLabels,
+				Annotations: journal.OriginalAnnotations,
+			},
+		}
+		if err := json.Unmarshal(journal.DesiredSpec, &recreated.Spec); err != nil {
+			return fmt.Errorf("error unmarshaling desired spec: %v", err)
+		}
+		if _, err := deployments.Create(ctx, recreated, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("error recreating deployment %s: %v", journal.Name, err)
+		}
+		if err := waitDeploymentReady(ctx, client, journal.Namespace, journal.Name); err != nil {
+			return fmt.Errorf("error waiting for recreated deployment %s: %v", journal.Name, err)
+		}
+
+		if err := deployments.Delete(ctx, journal.ShadowName, metav1.DeleteOptions{}); err != nil {
+			return fmt.Errorf("error deleting shadow deployment %s: %v", journal.ShadowName, err)
+		}
+		journal.Phase = PhaseComplete
+		if err := saveJournal(journal); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Migration %s complete for deployment %s/%s\n", journal.UID, journal.Namespace, journal.Name)
+	return removeJournal(journal.UID)
+}
+
+// waitDeploymentReady blocks on a watch of the named Deployment until
+// ReadyReplicas reaches the desired replica count.
+func waitDeploymentReady(ctx context.Context, client kubernetes.Interface, namespace, name string) error {
+	watcher, err := client.AppsV1().Deployments(namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String(),
+	})
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return fmt.Errorf("watch closed before deployment %s became ready", name)
+			}
+			dep, ok := event.Object.(*appsv1.Deployment)
+			if !ok {
+				continue
+			}
+			desired := int32(1)
+			if dep.Spec.Replicas != nil {
+				desired = *dep.Spec.Replicas
+			}
+			if dep.Status.ReadyReplicas == desired {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// deleteAndWaitDeployment deletes name with foreground propagation and
+// blocks until the watch reports the Deleted event, rather than assuming the
+// Delete call alone means the pods are gone.
+func deleteAndWaitDeployment(ctx context.Context, client kubernetes.Interface, namespace, name string) error {
+	watcher, err := client.AppsV1().Deployments(namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String(),
+	})
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	propagation := metav1.DeletePropagationForeground
+	if err := client.AppsV1().Deployments(namespace).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return fmt.Errorf("watch closed before deployment %s finished deleting", name)
+			}
+			if event.Type == watch.Deleted {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func shortSHA(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+	}
+	h.Write([]byte(time.Now().String()))
+	return hex.EncodeToString(h.Sum(nil))[:8]
+}
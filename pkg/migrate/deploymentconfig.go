@@ -0,0 +1,205 @@
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	ocpappsv1 "github.com/openshift/api/apps/v1"
+	ocpappsv1client "github.com/openshift/client-go/apps/clientset/versioned/typed/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+
+	"github.com/faizanbashir/k8s-go-sdk-examples/pkg/workloads"
+)
+
+// MigrateImmutableDeploymentConfig is the DeploymentConfig counterpart of
+// MigrateImmutable, following the same shadow/delete/recreate workflow.
+func MigrateImmutableDeploymentConfig(ctx context.Context, client ocpappsv1client.AppsV1Interface, ref workloads.Reference, mutator func(*ocpappsv1.DeploymentConfig) *ocpappsv1.DeploymentConfig) error {
+	original, err := client.DeploymentConfigs(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting deploymentconfig %s: %v", ref, err)
+	}
+
+	mutated := mutator(original.DeepCopy())
+	desiredSpec, err := json.Marshal(mutated.Spec)
+	if err != nil {
+		return fmt.Errorf("error marshaling mutated spec for %s: %v", ref, err)
+	}
+
+	uid := shortSHA(ref.Namespace, ref.Name)
+	journal := &Journal{
+		UID:                 uid,
+		Kind:                workloads.KindDeploymentConfig,
+		Namespace:           ref.Namespace,
+		Name:                ref.Name,
+		ShadowName:          fmt.Sprintf("%s-migrate-%s", ref.Name, uid),
+		Phase:               "",
+		DesiredSpec:         desiredSpec,
+		OriginalLabels:      original.Labels,
+		OriginalAnnotations: original.Annotations,
+		Ref:                 ref,
+	}
+
+	return runDeploymentConfigMigration(ctx, client, journal, original)
+}
+
+// MigrateResumeDeploymentConfig continues a DeploymentConfig migration from
+// whichever phase its journal last completed.
+func MigrateResumeDeploymentConfig(ctx context.Context, client ocpappsv1client.AppsV1Interface, uid string) error {
+	journal, err := LoadJournal(uid)
+	if err != nil {
+		return err
+	}
+	if journal.Kind != workloads.KindDeploymentConfig {
+		return fmt.Errorf("journal %s is for kind %s, not DeploymentConfig", uid, journal.Kind)
+	}
+
+	original, err := client.DeploymentConfigs(journal.Namespace).Get(ctx, journal.Name, metav1.GetOptions{})
+	if err != nil && journal.Phase == "" {
+		return fmt.Errorf("error getting deploymentconfig %s/%s: %v", journal.Namespace, journal.Name, err)
+	}
+	return runDeploymentConfigMigration(ctx, client, journal, original)
+}
+
+func runDeploymentConfigMigration(ctx context.Context, client ocpappsv1client.AppsV1Interface, journal *Journal, original *ocpappsv1.DeploymentConfig) error {
+	dcs := client.DeploymentConfigs(journal.Namespace)
+
+	if journal.Phase == "" {
+		shadow := original.DeepCopy()
+		shadow.ObjectMeta = metav1.ObjectMeta{
+			Name:      journal.ShadowName,
+			Namespace: journal.Namespace,
+			Labels:    original.Labels,
+		}
+		if err := json.Unmarshal(journal.DesiredSpec, &shadow.Spec); err != nil {
+			return fmt.Errorf("error unmarshaling desired spec: %v", err)
+		}
+		for k, v := range original.Spec.Template.Labels {
+			if shadow.Spec.Template.Labels == nil {
+				shadow.Spec.Template.Labels = map[string]string{}
+			}
+			if _, exists := shadow.Spec.Template.Labels[k]; !exists {
+				shadow.Spec.Template.Labels[k] = v
+			}
+		}
+
+		if _, err := dcs.Create(ctx, shadow, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("error creating shadow deploymentconfig %s: %v", journal.ShadowName, err)
+		}
+		fmt.Printf("Created shadow deploymentconfig %s for migration %s\n", journal.ShadowName, journal.UID)
+		journal.Phase = PhaseShadowCreated
+		if err := saveJournal(journal); err != nil {
+			return err
+		}
+	}
+
+	if journal.Phase == PhaseShadowCreated {
+		if err := waitDeploymentConfigReady(ctx, client, journal.Namespace, journal.ShadowName); err != nil {
+			return fmt.Errorf("error waiting for shadow deploymentconfig %s: %v", journal.ShadowName, err)
+		}
+		journal.Phase = PhaseShadowReady
+		if err := saveJournal(journal); err != nil {
+			return err
+		}
+	}
+
+	if journal.Phase == PhaseShadowReady {
+		if err := deleteAndWaitDeploymentConfig(ctx, client, journal.Namespace, journal.Name); err != nil {
+			return fmt.Errorf("error deleting original deploymentconfig %s: %v", journal.Name, err)
+		}
+		journal.Phase = PhaseOriginalDeleted
+		if err := saveJournal(journal); err != nil {
+			return err
+		}
+	}
+
+	if journal.Phase == PhaseOriginalDeleted {
+		recreated := &ocpappsv1.DeploymentConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        journal.Name,
+				Namespace:   journal.Namespace,
+				Labels:      journal.OriginalLabels,
+				Annotations: journal.OriginalAnnotations,
+			},
+		}
+		if err := json.Unmarshal(journal.DesiredSpec, &recreated.Spec); err != nil {
+			return fmt.Errorf("error unmarshaling desired spec: %v", err)
+		}
+		if _, err := dcs.Create(ctx, recreated, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("error recreating deploymentconfig %s: %v", journal.Name, err)
+		}
+		if err := waitDeploymentConfigReady(ctx, client, journal.Namespace, journal.Name); err != nil {
+			return fmt.Errorf("error waiting for recreated deploymentconfig %s: %v", journal.Name, err)
+		}
+
+		if err := dcs.Delete(ctx, journal.ShadowName, metav1.DeleteOptions{}); err != nil {
+			return fmt.Errorf("error deleting shadow deploymentconfig %s: %v", journal.ShadowName, err)
+		}
+		journal.Phase = PhaseComplete
+		if err := saveJournal(journal); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Migration %s complete for deploymentconfig %s/%s\n", journal.UID, journal.Namespace, journal.Name)
+	return removeJournal(journal.UID)
+}
+
+func waitDeploymentConfigReady(ctx context.Context, client ocpappsv1client.AppsV1Interface, namespace, name string) error {
+	watcher, err := client.DeploymentConfigs(namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String(),
+	})
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return fmt.Errorf("watch closed before deploymentconfig %s became ready", name)
+			}
+			dc, ok := event.Object.(*ocpappsv1.DeploymentConfig)
+			if !ok {
+				continue
+			}
+			if dc.Status.ReadyReplicas == dc.Spec.Replicas {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func deleteAndWaitDeploymentConfig(ctx context.Context, client ocpappsv1client.AppsV1Interface, namespace, name string) error {
+	watcher, err := client.DeploymentConfigs(namespace).Watch(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String(),
+	})
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+
+	propagation := metav1.DeletePropagationForeground
+	if err := client.DeploymentConfigs(namespace).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return fmt.Errorf("watch closed before deploymentconfig %s finished deleting", name)
+			}
+			if event.Type == watch.Deleted {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
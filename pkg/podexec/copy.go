@@ -0,0 +1,126 @@
+package podexec
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// CopyToPod tars localPath (a file or directory) and streams it into
+// ref's container, extracting under remoteDir via `tar xf -`.
+func CopyToPod(ctx context.Context, config *rest.Config, client kubernetes.Interface, ref PodRef, localPath, remoteDir string) error {
+	pipeReader, pipeWriter := io.Pipe()
+
+	go func() {
+		err := tarDirectory(localPath, pipeWriter)
+		pipeWriter.CloseWithError(err)
+	}()
+
+	var stderr bytes.Buffer
+	err := ExecStream(ctx, config, client, ref, []string{"tar", "xf", "-", "-C", remoteDir}, Options{}, pipeReader, io.Discard, &stderr)
+	if err != nil {
+		return fmt.Errorf("error copying %s to %s:%s: %v (stderr: %s)", localPath, ref.Name, remoteDir, err, stderr.String())
+	}
+	return nil
+}
+
+// CopyFromPod runs `tar cf -` over remotePath inside ref's container and
+// extracts the resulting archive under localDir.
+func CopyFromPod(ctx context.Context, config *rest.Config, client kubernetes.Interface, ref PodRef, remotePath, localDir string) error {
+	pipeReader, pipeWriter := io.Pipe()
+	remoteDir, remoteBase := filepath.Split(filepath.Clean(remotePath))
+	if remoteDir == "" {
+		remoteDir = "."
+	}
+
+	var stderr bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		err := ExecStream(ctx, config, client, ref, []string{"tar", "cf", "-", "-C", remoteDir, remoteBase}, Options{}, nil, pipeWriter, &stderr)
+		pipeWriter.CloseWithError(err)
+		done <- err
+	}()
+
+	if err := untar(pipeReader, localDir); err != nil {
+		<-done
+		return fmt.Errorf("error extracting archive from %s:%s: %v", ref.Name, remotePath, err)
+	}
+	if err := <-done; err != nil {
+		return fmt.Errorf("error copying %s:%s to %s: %v (stderr: %s)", ref.Name, remotePath, localDir, err, stderr.String())
+	}
+	return nil
+}
+
+func tarDirectory(root string, w io.Writer) error {
+	tw := tar.NewWriter(w)
+	defer tw.Close()
+
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(filepath.Dir(root), path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = relPath
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(tw, file)
+		return err
+	})
+}
+
+func untar(r io.Reader, destDir string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(destDir, header.Name)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode))
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(file, tr); err != nil {
+				file.Close()
+				return err
+			}
+			file.Close()
+		}
+	}
+}
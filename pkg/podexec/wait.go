@@ -0,0 +1,60 @@
+package podexec
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// WaitForPodRunning blocks until the named pod reaches Running phase, using
+// an informer rather than polling so callers can chain
+// "create deployment -> wait -> exec" without hand-rolled retry loops.
+func WaitForPodRunning(ctx context.Context, client kubernetes.Interface, namespace, name string) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(client, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", name).String()
+		}),
+	)
+	informer := factory.Core().V1().Pods().Informer()
+
+	done := make(chan error, 1)
+	notifyIfRunning := func(obj interface{}) {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || pod.Name != name {
+			return
+		}
+		if pod.Status.Phase == corev1.PodRunning {
+			select {
+			case done <- nil:
+			default:
+			}
+		}
+	}
+
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    notifyIfRunning,
+		UpdateFunc: func(_, newObj interface{}) { notifyIfRunning(newObj) },
+	})
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go informer.Run(runCtx.Done())
+
+	if !cache.WaitForCacheSync(runCtx.Done(), informer.HasSynced) {
+		return fmt.Errorf("podexec: timed out waiting for informer cache to sync for pod %s/%s", namespace, name)
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("podexec: timed out waiting for pod %s/%s to be running: %v", namespace, name, ctx.Err())
+	}
+}
@@ -0,0 +1,75 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/faizanbashir/k8s-go-sdk-examples/pkg/workloads"
+)
+
+// checkHPA looks for a HorizontalPodAutoscaler whose scaleTargetRef points at
+// ref. If one is found and opts.Force is false, the scale is either widened
+// into the HPA's min/max window (RespectHPA) or blocked outright. hpaManaged
+// reports whether an HPA owns ref's replica count at all - the caller must
+// not issue a direct replica patch in that case, since the HPA controller
+// reconciles it away almost immediately.
+func (s *Scaler) checkHPA(ctx context.Context, ref workloads.Reference, replicas int32, opts StrategyOptions, events chan<- ScaleEvent) (blocked, hpaManaged bool, err error) {
+	hpas, err := s.client.AutoscalingV2().HorizontalPodAutoscalers(ref.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return false, false, fmt.Errorf("error listing HorizontalPodAutoscalers in %s: %v", ref.Namespace, err)
+	}
+
+	var matched *autoscalingv2.HorizontalPodAutoscaler
+	for i := range hpas.Items {
+		hpa := &hpas.Items[i]
+		if hpa.Spec.ScaleTargetRef.Kind == string(ref.Kind) && hpa.Spec.ScaleTargetRef.Name == ref.Name {
+			matched = hpa
+			break
+		}
+	}
+	if matched == nil {
+		return false, false, nil
+	}
+
+	if !opts.RespectHPA {
+		events <- ScaleEvent{
+			Ref:     ref,
+			Phase:   PhaseBlocked,
+			Message: fmt.Sprintf("%s is managed by HorizontalPodAutoscaler %s; pass Force or RespectHPA", ref, matched.Name),
+		}
+		return true, true, nil
+	}
+
+	minReplicas := int32(1)
+	if matched.Spec.MinReplicas != nil {
+		minReplicas = *matched.Spec.MinReplicas
+	}
+	newMin, newMax := minReplicas, matched.Spec.MaxReplicas
+	widened := false
+	if replicas < newMin {
+		newMin = replicas
+		widened = true
+	}
+	if replicas > newMax {
+		newMax = replicas
+		widened = true
+	}
+	if !widened {
+		return false, true, nil
+	}
+
+	matched.Spec.MinReplicas = &newMin
+	matched.Spec.MaxReplicas = newMax
+	if _, err := s.client.AutoscalingV2().HorizontalPodAutoscalers(ref.Namespace).Update(ctx, matched, metav1.UpdateOptions{}); err != nil {
+		return false, false, fmt.Errorf("error widening HorizontalPodAutoscaler %s window: %v", matched.Name, err)
+	}
+	events <- ScaleEvent{
+		Ref:     ref,
+		Phase:   PhaseChecking,
+		Message: fmt.Sprintf("widened HorizontalPodAutoscaler %s window to [%d,%d] to allow scaling %s to %d", matched.Name, newMin, newMax, ref, replicas),
+	}
+	return false, true, nil
+}
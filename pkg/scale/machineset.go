@@ -0,0 +1,117 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/faizanbashir/k8s-go-sdk-examples/pkg/workloads"
+)
+
+// machineSetLabel is the label machine-api stamps onto every Machine with
+// the MachineSet that owns it.
+const machineSetLabel = "machine.openshift.io/cluster-api-machineset"
+
+// cordonForScaleDown cordons `count` nodes backing ref's Machines before the
+// MachineSet itself is scaled down, so the scheduler stops placing new pods
+// on a node that's about to disappear. It does not evict anything itself -
+// see checkNodeDrained - it only refuses to proceed if a node still has
+// non-DaemonSet pods running, rather than decrementing blind and orphaning
+// them. Any node this call cordoned is uncordoned again before it returns an
+// error, so a failed scale-down doesn't leave nodes stuck cordoned.
+func (s *Scaler) cordonForScaleDown(ctx context.Context, ref workloads.Reference, count int32) error {
+	if s.machine == nil {
+		return fmt.Errorf("no OpenShift machine client configured for %s", ref)
+	}
+
+	machines, err := s.machine.Machines(ref.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", machineSetLabel, ref.Name),
+	})
+	if err != nil {
+		return fmt.Errorf("error listing machines for machineset %s: %v", ref, err)
+	}
+
+	var cordonedByUs []string
+	fail := func(err error) error {
+		for _, nodeName := range cordonedByUs {
+			if uncordonErr := s.uncordonNode(ctx, nodeName); uncordonErr != nil {
+				fmt.Printf("error uncordoning node %s after failed scale-down of %s: %v\n", nodeName, ref, uncordonErr)
+			}
+		}
+		return err
+	}
+
+	cordoned := int32(0)
+	for _, m := range machines.Items {
+		if cordoned >= count {
+			break
+		}
+		if m.Status.NodeRef == nil {
+			continue
+		}
+		nodeName := m.Status.NodeRef.Name
+
+		node, err := s.client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			return fail(fmt.Errorf("error getting node %s for machine %s: %v", nodeName, m.Name, err))
+		}
+		if !node.Spec.Unschedulable {
+			patch := []byte(`{"spec":{"unschedulable":true}}`)
+			if _, err := s.client.CoreV1().Nodes().Patch(ctx, nodeName, types.StrategicMergePatchType, patch, metav1.PatchOptions{}); err != nil {
+				return fail(fmt.Errorf("error cordoning node %s: %v", nodeName, err))
+			}
+			cordonedByUs = append(cordonedByUs, nodeName)
+			fmt.Printf("Cordoned node %s ahead of scaling down machineset %s\n", nodeName, ref.Name)
+		}
+
+		drained, err := s.checkNodeDrained(ctx, nodeName)
+		if err != nil {
+			return fail(fmt.Errorf("error checking drain status of node %s: %v", nodeName, err))
+		}
+		if !drained {
+			return fail(fmt.Errorf("node %s still has pods scheduled; drain it before scaling down %s", nodeName, ref))
+		}
+
+		cordoned++
+	}
+	return nil
+}
+
+// uncordonNode marks a node schedulable again.
+func (s *Scaler) uncordonNode(ctx context.Context, nodeName string) error {
+	patch := []byte(`{"spec":{"unschedulable":false}}`)
+	_, err := s.client.CoreV1().Nodes().Patch(ctx, nodeName, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// checkNodeDrained reports whether a node has no pods left other than ones
+// owned by a DaemonSet, which never get evicted by a drain. It only checks:
+// it does not evict any pods itself, so a node with real workloads still on
+// it makes cordonForScaleDown fail rather than force pods off.
+func (s *Scaler) checkNodeDrained(ctx context.Context, nodeName string) (bool, error) {
+	pods, err := s.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + nodeName,
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, pod := range pods.Items {
+		if isDaemonSetPod(&pod) {
+			continue
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+func isDaemonSetPod(pod *corev1.Pod) bool {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Kind == "DaemonSet" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ocpappsv1 "github.com/openshift/api/apps/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/faizanbashir/k8s-go-sdk-examples/pkg/workloads"
+)
+
+// scaleDownRespectingPDB scales ref down from current to target, re-reading
+// every matching PodDisruptionBudget's Status.DisruptionsAllowed before each
+// step instead of trusting a single snapshot taken up front - a PDB's budget
+// can refresh (or run out) as pods are evicted elsewhere in the cluster, and
+// a plan computed once at the start would drive straight through a budget
+// that's since hit zero. handled is false only when no PDB matches ref, in
+// which case the caller falls back to its own Stepped/direct scaling.
+func (s *Scaler) scaleDownRespectingPDB(ctx context.Context, ref workloads.Reference, current, target int32, opts StrategyOptions, events chan<- ScaleEvent) (handled bool, err error) {
+	pdbs, err := s.matchingPDBs(ctx, ref)
+	if err != nil {
+		return true, err
+	}
+	if len(pdbs) == 0 {
+		return false, nil
+	}
+
+	if name, ok := unsatisfiableMinAvailable(pdbs, target); ok {
+		events <- ScaleEvent{
+			Ref:      ref,
+			Phase:    PhaseBlocked,
+			Replicas: current,
+			Message:  fmt.Sprintf("scaling %s to %d would permanently violate PodDisruptionBudget %s's minAvailable; raise the target or lower the PDB's minAvailable first", ref, target, name),
+		}
+		return true, nil
+	}
+
+	_, err = s.stepScaleDownPDB(ctx, ref, current, target, opts, events)
+	return true, err
+}
+
+// stepScaleDownPDB drives ref from current down to target one PDB-aware step
+// at a time, re-listing matching PDBs before every step. It returns once
+// target is reached or it has emitted a terminal Blocked/Error event; the
+// replica count it last successfully scaled to is returned for logging by
+// the caller.
+func (s *Scaler) stepScaleDownPDB(ctx context.Context, ref workloads.Reference, current, target int32, opts StrategyOptions, events chan<- ScaleEvent) (int32, error) {
+	step := current
+	for i := 1; step > target; i++ {
+		pdbs, err := s.matchingPDBs(ctx, ref)
+		if err != nil {
+			return step, err
+		}
+		if len(pdbs) == 0 {
+			// The last matching PDB is gone; nothing left to pace against.
+			if err := s.workloads.Scale(ctx, ref, target); err != nil {
+				return step, fmt.Errorf("error scaling %s to %d: %v", ref, target, err)
+			}
+			step = target
+			break
+		}
+
+		remaining := step - target
+		allowed := minDisruptionsAllowed(pdbs)
+		if allowed <= 0 {
+			events <- ScaleEvent{
+				Ref:      ref,
+				Phase:    PhaseBlocked,
+				Replicas: step,
+				Message:  fmt.Sprintf("PodDisruptionBudget for %s currently allows no disruptions; stopped at %d, retry once it recovers", ref, step),
+			}
+			return step, nil
+		}
+		if allowed < remaining && !opts.RespectPDB {
+			events <- ScaleEvent{
+				Ref:      ref,
+				Phase:    PhaseBlocked,
+				Replicas: step,
+				Message:  fmt.Sprintf("scaling %s to %d would violate a PodDisruptionBudget; pass Force or RespectPDB", ref, target),
+			}
+			return step, nil
+		}
+
+		maxStep := minInt32(allowed, remaining)
+		if opts.Stepped && maxStep > 1 {
+			maxStep = 1
+		}
+		next := step - maxStep
+
+		events <- ScaleEvent{Ref: ref, Phase: PhaseStepping, Replicas: next, Message: fmt.Sprintf("step %d: scaling %s from %d to %d", i, ref, step, next)}
+		if err := s.workloads.Scale(ctx, ref, next); err != nil {
+			return step, fmt.Errorf("error scaling %s to %d: %v", ref, next, err)
+		}
+		step = next
+
+		if step > target {
+			select {
+			case <-ctx.Done():
+				return step, ctx.Err()
+			case <-time.After(opts.Interval):
+			}
+		}
+	}
+
+	events <- ScaleEvent{Ref: ref, Phase: PhaseComplete, Replicas: step, Message: fmt.Sprintf("scaled %s to %d replicas", ref, step)}
+	return step, nil
+}
+
+func minInt32(a, b int32) int32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// matchingPDBs lists every PodDisruptionBudget in ref's namespace whose
+// selector matches ref's pod template labels.
+func (s *Scaler) matchingPDBs(ctx context.Context, ref workloads.Reference) ([]policyv1.PodDisruptionBudget, error) {
+	podLabels, err := s.podTemplateLabels(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	pdbs, err := s.client.PolicyV1().PodDisruptionBudgets(ref.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing PodDisruptionBudgets in %s: %v", ref.Namespace, err)
+	}
+
+	var matched []policyv1.PodDisruptionBudget
+	for _, pdb := range pdbs.Items {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing selector for PodDisruptionBudget %s: %v", pdb.Name, err)
+		}
+		if selector.Matches(labels.Set(podLabels)) {
+			matched = append(matched, pdb)
+		}
+	}
+	return matched, nil
+}
+
+// unsatisfiableMinAvailable reports whether target is below any matching
+// PDB's integer minAvailable, which makes the target unreachable no matter
+// how the scale-down is paced: minAvailable pods must stay up at all times,
+// so disruptions never refresh enough to go below it.
+func unsatisfiableMinAvailable(pdbs []policyv1.PodDisruptionBudget, target int32) (name string, unsatisfiable bool) {
+	for _, pdb := range pdbs {
+		if pdb.Spec.MinAvailable == nil || pdb.Spec.MinAvailable.Type != intstr.Int {
+			continue
+		}
+		if target < int32(pdb.Spec.MinAvailable.IntValue()) {
+			return pdb.Name, true
+		}
+	}
+	return "", false
+}
+
+// minDisruptionsAllowed returns the smallest Status.DisruptionsAllowed across
+// pdbs, read fresh by the caller immediately before each step.
+func minDisruptionsAllowed(pdbs []policyv1.PodDisruptionBudget) int32 {
+	min := pdbs[0].Status.DisruptionsAllowed
+	for _, pdb := range pdbs[1:] {
+		if pdb.Status.DisruptionsAllowed < min {
+			min = pdb.Status.DisruptionsAllowed
+		}
+	}
+	return min
+}
+
+// podTemplateLabels fetches the pod template labels for ref, which is what
+// PodDisruptionBudget selectors are matched against.
+func (s *Scaler) podTemplateLabels(ctx context.Context, ref workloads.Reference) (map[string]string, error) {
+	switch ref.Kind {
+	case workloads.KindDeployment:
+		dep, err := s.client.AppsV1().Deployments(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error getting deployment %s: %v", ref, err)
+		}
+		return templateLabels(dep), nil
+	case workloads.KindStatefulSet:
+		sts, err := s.client.AppsV1().StatefulSets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error getting statefulset %s: %v", ref, err)
+		}
+		return sts.Spec.Template.Labels, nil
+	case workloads.KindDeploymentConfig:
+		if s.ocpApps == nil {
+			return nil, fmt.Errorf("no OpenShift apps client configured for %s", ref)
+		}
+		dc, err := s.ocpApps.DeploymentConfigs(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error getting deploymentconfig %s: %v", ref, err)
+		}
+		return dcTemplateLabels(dc), nil
+	default:
+		return nil, fmt.Errorf("pod disruption budget lookup unsupported for kind %q", ref.Kind)
+	}
+}
+
+func templateLabels(dep *appsv1.Deployment) map[string]string {
+	return dep.Spec.Template.Labels
+}
+
+func dcTemplateLabels(dc *ocpappsv1.DeploymentConfig) map[string]string {
+	if dc.Spec.Template == nil {
+		return nil
+	}
+	return dc.Spec.Template.Labels
+}
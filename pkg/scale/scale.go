@@ -0,0 +1,201 @@
+// Package scale turns the bare replica-count JSON patches in
+// ScaleDeploymentConfig, ScaleDeployment and the MachineSet scaler into a
+// pre-flight-aware scaling subsystem: before patching, it checks for an
+// HorizontalPodAutoscaler fighting the change, checks whether a
+// PodDisruptionBudget would be violated by a scale-down, and for MachineSets
+// cordons/drains nodes instead of just decrementing a count and leaving pods
+// orphaned.
+package scale
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ocpappsv1client "github.com/openshift/client-go/apps/clientset/versioned/typed/apps/v1"
+	machinev1beta1client "github.com/openshift/client-go/machine/clientset/versioned/typed/machine/v1beta1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/faizanbashir/k8s-go-sdk-examples/pkg/workloads"
+)
+
+// StrategyOptions controls how a scale operation is carried out.
+type StrategyOptions struct {
+	// Force skips the HPA pre-flight check and scales directly.
+	Force bool
+	// RespectHPA, when a targeting HPA is found and Force is false, widens
+	// the HPA's min/max window to include the requested count instead of
+	// refusing the scale.
+	RespectHPA bool
+	// RespectPDB paces a scale-down in steps that keep any matching
+	// PodDisruptionBudget satisfied, instead of refusing it outright.
+	RespectPDB bool
+	// Stepped scales down one replica at a time with Interval between
+	// steps, regardless of RespectPDB, so progress can be observed.
+	Stepped bool
+	// Interval is the pause between steps when Stepped or RespectPDB-paced
+	// scaling is in effect. Defaults to 10s.
+	Interval time.Duration
+}
+
+// ScaleEventPhase identifies which stage of the scale workflow an event was
+// emitted from.
+type ScaleEventPhase string
+
+const (
+	PhaseChecking ScaleEventPhase = "checking"
+	PhaseStepping ScaleEventPhase = "stepping"
+	PhaseBlocked  ScaleEventPhase = "blocked"
+	PhaseComplete ScaleEventPhase = "complete"
+	PhaseError    ScaleEventPhase = "error"
+	// PhaseHPAManaged means a HorizontalPodAutoscaler owns ref's replica
+	// count, so no direct replica patch was issued - the HPA controller
+	// would just reconcile it away. Only its min/max window, if anything,
+	// was adjusted.
+	PhaseHPAManaged ScaleEventPhase = "hpa-managed"
+)
+
+// ScaleEvent reports a single step of progress for a Scale call.
+type ScaleEvent struct {
+	Ref      workloads.Reference
+	Phase    ScaleEventPhase
+	Replicas int32
+	Message  string
+	Err      error
+}
+
+// Scaler drives pre-flight-aware scaling for Deployments, DeploymentConfigs
+// and MachineSets.
+type Scaler struct {
+	client    kubernetes.Interface
+	ocpApps   ocpappsv1client.AppsV1Interface
+	machine   machinev1beta1client.MachineV1beta1Interface
+	workloads workloads.WorkloadClient
+}
+
+// NewScaler builds a Scaler over the given typed clients. ocpApps and
+// machine may be nil if the cluster isn't OpenShift; Scale then fails for
+// DeploymentConfig/MachineSet references with a clear error.
+func NewScaler(client kubernetes.Interface, ocpApps ocpappsv1client.AppsV1Interface, machine machinev1beta1client.MachineV1beta1Interface) *Scaler {
+	return &Scaler{
+		client:    client,
+		ocpApps:   ocpApps,
+		machine:   machine,
+		workloads: workloads.NewClient(client, ocpApps, machine),
+	}
+}
+
+// Scale runs the pre-flight checks for ref and, once they pass, scales it to
+// replicas according to opts. Progress is reported on the returned channel,
+// which is closed once the scale completes, is blocked, or errors.
+func (s *Scaler) Scale(ctx context.Context, ref workloads.Reference, replicas int32, opts StrategyOptions) <-chan ScaleEvent {
+	if opts.Interval == 0 {
+		opts.Interval = 10 * time.Second
+	}
+	events := make(chan ScaleEvent, 8)
+
+	go func() {
+		defer close(events)
+		s.run(ctx, ref, replicas, opts, events)
+	}()
+
+	return events
+}
+
+func (s *Scaler) run(ctx context.Context, ref workloads.Reference, replicas int32, opts StrategyOptions, events chan<- ScaleEvent) {
+	status, err := s.workloads.RolloutStatus(ctx, ref)
+	if err != nil {
+		events <- ScaleEvent{Ref: ref, Phase: PhaseError, Err: fmt.Errorf("error reading current state of %s: %v", ref, err)}
+		return
+	}
+	current := status.DesiredReplicas
+	scalingDown := replicas < current
+
+	events <- ScaleEvent{Ref: ref, Phase: PhaseChecking, Replicas: current, Message: fmt.Sprintf("checking pre-flight conditions before scaling %s from %d to %d", ref, current, replicas)}
+
+	if !opts.Force {
+		blocked, hpaManaged, err := s.checkHPA(ctx, ref, replicas, opts, events)
+		if err != nil {
+			events <- ScaleEvent{Ref: ref, Phase: PhaseError, Err: err}
+			return
+		}
+		if blocked {
+			return
+		}
+		if hpaManaged {
+			// The HPA controller reconciles any direct replica patch away
+			// almost immediately, so there's nothing further to do here.
+			events <- ScaleEvent{Ref: ref, Phase: PhaseHPAManaged, Replicas: replicas, Message: fmt.Sprintf("%s's replica count is controlled by a HorizontalPodAutoscaler; no direct scale was issued", ref)}
+			return
+		}
+	}
+
+	if scalingDown && ref.Kind != workloads.KindMachineSet {
+		handled, err := s.scaleDownRespectingPDB(ctx, ref, current, replicas, opts, events)
+		if err != nil {
+			events <- ScaleEvent{Ref: ref, Phase: PhaseError, Err: err}
+			return
+		}
+		if handled {
+			return
+		}
+	}
+
+	if ref.Kind == workloads.KindMachineSet && scalingDown {
+		if err := s.cordonForScaleDown(ctx, ref, current-replicas); err != nil {
+			events <- ScaleEvent{Ref: ref, Phase: PhaseError, Err: fmt.Errorf("error preparing nodes for scale-down of %s: %v", ref, err)}
+			return
+		}
+	}
+
+	if opts.Stepped && current != replicas {
+		steps := steppedPlan(current, replicas)
+		s.scaleInSteps(ctx, ref, steps, opts, events)
+		return
+	}
+
+	if err := s.workloads.Scale(ctx, ref, replicas); err != nil {
+		events <- ScaleEvent{Ref: ref, Phase: PhaseError, Err: fmt.Errorf("error scaling %s: %v", ref, err)}
+		return
+	}
+	events <- ScaleEvent{Ref: ref, Phase: PhaseComplete, Replicas: replicas, Message: fmt.Sprintf("scaled %s to %d replicas", ref, replicas)}
+}
+
+func (s *Scaler) scaleInSteps(ctx context.Context, ref workloads.Reference, steps []int32, opts StrategyOptions, events chan<- ScaleEvent) {
+	for i, step := range steps {
+		events <- ScaleEvent{Ref: ref, Phase: PhaseStepping, Replicas: step, Message: fmt.Sprintf("step %d/%d: scaling %s to %d", i+1, len(steps), ref, step)}
+		if err := s.workloads.Scale(ctx, ref, step); err != nil {
+			events <- ScaleEvent{Ref: ref, Phase: PhaseError, Err: fmt.Errorf("error scaling %s to %d: %v", ref, step, err)}
+			return
+		}
+		if i < len(steps)-1 {
+			select {
+			case <-ctx.Done():
+				events <- ScaleEvent{Ref: ref, Phase: PhaseError, Err: ctx.Err()}
+				return
+			case <-time.After(opts.Interval):
+			}
+		}
+	}
+	events <- ScaleEvent{Ref: ref, Phase: PhaseComplete, Replicas: steps[len(steps)-1], Message: fmt.Sprintf("scaled %s to %d replicas", ref, steps[len(steps)-1])}
+}
+
+// steppedPlan returns the sequence of replica counts to pass through to get
+// from current to target one step at a time.
+func steppedPlan(current, target int32) []int32 {
+	if current == target {
+		return []int32{target}
+	}
+	step := int32(1)
+	if target < current {
+		step = -1
+	}
+	steps := make([]int32, 0)
+	for n := current + step; ; n += step {
+		steps = append(steps, n)
+		if n == target {
+			break
+		}
+	}
+	return steps
+}
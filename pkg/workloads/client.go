@@ -0,0 +1,87 @@
+package workloads
+
+import (
+	"context"
+	"fmt"
+
+	ocpappsv1client "github.com/openshift/client-go/apps/clientset/versioned/typed/apps/v1"
+	machinev1beta1client "github.com/openshift/client-go/machine/clientset/versioned/typed/machine/v1beta1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// multiClient dispatches WorkloadClient calls to the per-kind adapter
+// matching Reference.Kind, so callers don't need to know which underlying
+// typed client backs a given workload.
+type multiClient struct {
+	deployment       *deploymentClient
+	statefulSet      *statefulSetClient
+	daemonSet        *daemonSetClient
+	deploymentConfig *deploymentConfigClient
+	machineSet       *machineSetClient
+}
+
+// NewClient builds a WorkloadClient backed by the given Kubernetes and
+// OpenShift typed clients. Either of the OpenShift clients may be nil if the
+// cluster doesn't run OpenShift; calls referencing those kinds then fail
+// with a clear error instead of a nil pointer panic.
+func NewClient(client kubernetes.Interface, ocpApps ocpappsv1client.AppsV1Interface, machine machinev1beta1client.MachineV1beta1Interface) WorkloadClient {
+	m := &multiClient{
+		deployment:  &deploymentClient{client: client},
+		statefulSet: &statefulSetClient{client: client},
+		daemonSet:   &daemonSetClient{client: client},
+	}
+	if ocpApps != nil {
+		m.deploymentConfig = &deploymentConfigClient{client: ocpApps}
+	}
+	if machine != nil {
+		m.machineSet = &machineSetClient{client: machine}
+	}
+	return m
+}
+
+func (m *multiClient) adapterFor(ref Reference) (WorkloadClient, error) {
+	switch ref.Kind {
+	case KindDeployment:
+		return m.deployment, nil
+	case KindStatefulSet:
+		return m.statefulSet, nil
+	case KindDaemonSet:
+		return m.daemonSet, nil
+	case KindDeploymentConfig:
+		if m.deploymentConfig == nil {
+			return nil, fmt.Errorf("no OpenShift apps client configured for %s", ref)
+		}
+		return m.deploymentConfig, nil
+	case KindMachineSet:
+		if m.machineSet == nil {
+			return nil, fmt.Errorf("no OpenShift machine client configured for %s", ref)
+		}
+		return m.machineSet, nil
+	default:
+		return nil, fmt.Errorf("unsupported workload kind %q", ref.Kind)
+	}
+}
+
+func (m *multiClient) Scale(ctx context.Context, ref Reference, replicas int32) error {
+	adapter, err := m.adapterFor(ref)
+	if err != nil {
+		return err
+	}
+	return adapter.Scale(ctx, ref, replicas)
+}
+
+func (m *multiClient) SetImage(ctx context.Context, ref Reference, container, image string) error {
+	adapter, err := m.adapterFor(ref)
+	if err != nil {
+		return err
+	}
+	return adapter.SetImage(ctx, ref, container, image)
+}
+
+func (m *multiClient) RolloutStatus(ctx context.Context, ref Reference) (RolloutStatus, error) {
+	adapter, err := m.adapterFor(ref)
+	if err != nil {
+		return RolloutStatus{}, err
+	}
+	return adapter.RolloutStatus(ctx, ref)
+}
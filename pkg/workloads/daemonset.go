@@ -0,0 +1,55 @@
+package workloads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// daemonSetClient adapts apps/v1 DaemonSets to WorkloadClient. DaemonSets
+// have no replica count: one pod per matching node, so Scale is rejected.
+type daemonSetClient struct {
+	client kubernetes.Interface
+}
+
+func (d *daemonSetClient) Scale(ctx context.Context, ref Reference, replicas int32) error {
+	return fmt.Errorf("daemonset %s does not support scaling: replica count is determined by node selection", ref)
+}
+
+func (d *daemonSetClient) SetImage(ctx context.Context, ref Reference, container, image string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []map[string]interface{}{
+						{"name": container, "image": image},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error building patch for daemonset %s: %v", ref, err)
+	}
+	_, err = d.client.AppsV1().DaemonSets(ref.Namespace).Patch(ctx, ref.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("error setting image for daemonset %s: %v", ref, err)
+	}
+	return nil
+}
+
+func (d *daemonSetClient) RolloutStatus(ctx context.Context, ref Reference) (RolloutStatus, error) {
+	ds, err := d.client.AppsV1().DaemonSets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return RolloutStatus{}, fmt.Errorf("error getting daemonset %s: %v", ref, err)
+	}
+	return RolloutStatus{
+		ObservedGeneration: ds.Status.ObservedGeneration,
+		DesiredReplicas:    ds.Status.DesiredNumberScheduled,
+		ReadyReplicas:      ds.Status.NumberReady,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package workloads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// deploymentClient adapts apps/v1 Deployments to WorkloadClient using
+// strategic-merge patches, which Deployments accept on every field we touch.
+type deploymentClient struct {
+	client kubernetes.Interface
+}
+
+func (d *deploymentClient) Scale(ctx context.Context, ref Reference, replicas int32) error {
+	patch := fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas)
+	_, err := d.client.AppsV1().Deployments(ref.Namespace).Patch(ctx, ref.Name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("error scaling deployment %s: %v", ref, err)
+	}
+	return nil
+}
+
+func (d *deploymentClient) SetImage(ctx context.Context, ref Reference, container, image string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []map[string]interface{}{
+						{"name": container, "image": image},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error building patch for deployment %s: %v", ref, err)
+	}
+	_, err = d.client.AppsV1().Deployments(ref.Namespace).Patch(ctx, ref.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("error setting image for deployment %s: %v", ref, err)
+	}
+	return nil
+}
+
+func (d *deploymentClient) RolloutStatus(ctx context.Context, ref Reference) (RolloutStatus, error) {
+	dep, err := d.client.AppsV1().Deployments(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return RolloutStatus{}, fmt.Errorf("error getting deployment %s: %v", ref, err)
+	}
+	desired := int32(1)
+	if dep.Spec.Replicas != nil {
+		desired = *dep.Spec.Replicas
+	}
+	return RolloutStatus{
+		ObservedGeneration: dep.Status.ObservedGeneration,
+		DesiredReplicas:    desired,
+		ReadyReplicas:      dep.Status.ReadyReplicas,
+	}, nil
+}
@@ -0,0 +1,83 @@
+package workloads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	ocpappsv1client "github.com/openshift/client-go/apps/clientset/versioned/typed/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// jsonPatchOp mirrors the ad-hoc stringPatch/integerPatch structs in
+// openshift/deploymentconfig and openshift/machinesets: one JSON-patch
+// operation with an interface{} value so the same type covers both.
+type jsonPatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// deploymentConfigClient adapts OpenShift DeploymentConfigs to
+// WorkloadClient. DeploymentConfig doesn't accept a strategic-merge patch on
+// every field, so it's driven with JSON patches like the original example.
+type deploymentConfigClient struct {
+	client ocpappsv1client.AppsV1Interface
+}
+
+func (d *deploymentConfigClient) Scale(ctx context.Context, ref Reference, replicas int32) error {
+	payload, err := json.Marshal([]jsonPatchOp{{Op: "replace", Path: "/spec/replicas", Value: replicas}})
+	if err != nil {
+		return fmt.Errorf("error building patch for deploymentconfig %s: %v", ref, err)
+	}
+	_, err = d.client.DeploymentConfigs(ref.Namespace).Patch(ctx, ref.Name, types.JSONPatchType, payload, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("error scaling deploymentconfig %s: %v", ref, err)
+	}
+	return nil
+}
+
+func (d *deploymentConfigClient) SetImage(ctx context.Context, ref Reference, container, image string) error {
+	dc, err := d.client.DeploymentConfigs(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting deploymentconfig %s: %v", ref, err)
+	}
+
+	containerIndex := -1
+	for i, c := range dc.Spec.Template.Spec.Containers {
+		if c.Name == container {
+			containerIndex = i
+			break
+		}
+	}
+	if containerIndex == -1 {
+		return fmt.Errorf("deploymentconfig %s has no container named %q", ref, container)
+	}
+
+	payload, err := json.Marshal([]jsonPatchOp{{
+		Op:    "replace",
+		Path:  fmt.Sprintf("/spec/template/spec/containers/%d/image", containerIndex),
+		Value: image,
+	}})
+	if err != nil {
+		return fmt.Errorf("error building patch for deploymentconfig %s: %v", ref, err)
+	}
+	_, err = d.client.DeploymentConfigs(ref.Namespace).Patch(ctx, ref.Name, types.JSONPatchType, payload, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("error setting image for deploymentconfig %s: %v", ref, err)
+	}
+	return nil
+}
+
+func (d *deploymentConfigClient) RolloutStatus(ctx context.Context, ref Reference) (RolloutStatus, error) {
+	dc, err := d.client.DeploymentConfigs(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return RolloutStatus{}, fmt.Errorf("error getting deploymentconfig %s: %v", ref, err)
+	}
+	return RolloutStatus{
+		ObservedGeneration: dc.Status.ObservedGeneration,
+		DesiredReplicas:    dc.Spec.Replicas,
+		ReadyReplicas:      dc.Status.ReadyReplicas,
+	}, nil
+}
@@ -0,0 +1,49 @@
+package workloads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	machinev1beta1client "github.com/openshift/client-go/machine/clientset/versioned/typed/machine/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// machineSetClient adapts OpenShift MachineSets to WorkloadClient using JSON
+// patches, the same approach openshift/machinesets already uses.
+type machineSetClient struct {
+	client machinev1beta1client.MachineV1beta1Interface
+}
+
+func (m *machineSetClient) Scale(ctx context.Context, ref Reference, replicas int32) error {
+	payload, err := json.Marshal([]jsonPatchOp{{Op: "replace", Path: "/spec/replicas", Value: replicas}})
+	if err != nil {
+		return fmt.Errorf("error building patch for machineset %s: %v", ref, err)
+	}
+	_, err = m.client.MachineSets(ref.Namespace).Patch(ctx, ref.Name, types.JSONPatchType, payload, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("error scaling machineset %s: %v", ref, err)
+	}
+	return nil
+}
+
+func (m *machineSetClient) SetImage(ctx context.Context, ref Reference, container, image string) error {
+	return fmt.Errorf("machineset %s has no container image to set", ref)
+}
+
+func (m *machineSetClient) RolloutStatus(ctx context.Context, ref Reference) (RolloutStatus, error) {
+	ms, err := m.client.MachineSets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return RolloutStatus{}, fmt.Errorf("error getting machineset %s: %v", ref, err)
+	}
+	desired := int32(1)
+	if ms.Spec.Replicas != nil {
+		desired = *ms.Spec.Replicas
+	}
+	return RolloutStatus{
+		ObservedGeneration: ms.Status.ObservedGeneration,
+		DesiredReplicas:    desired,
+		ReadyReplicas:      ms.Status.ReadyReplicas,
+	}, nil
+}
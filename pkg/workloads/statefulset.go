@@ -0,0 +1,64 @@
+package workloads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// statefulSetClient adapts apps/v1 StatefulSets to WorkloadClient using
+// strategic-merge patches.
+type statefulSetClient struct {
+	client kubernetes.Interface
+}
+
+func (s *statefulSetClient) Scale(ctx context.Context, ref Reference, replicas int32) error {
+	patch := fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas)
+	_, err := s.client.AppsV1().StatefulSets(ref.Namespace).Patch(ctx, ref.Name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("error scaling statefulset %s: %v", ref, err)
+	}
+	return nil
+}
+
+func (s *statefulSetClient) SetImage(ctx context.Context, ref Reference, container, image string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []map[string]interface{}{
+						{"name": container, "image": image},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error building patch for statefulset %s: %v", ref, err)
+	}
+	_, err = s.client.AppsV1().StatefulSets(ref.Namespace).Patch(ctx, ref.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("error setting image for statefulset %s: %v", ref, err)
+	}
+	return nil
+}
+
+func (s *statefulSetClient) RolloutStatus(ctx context.Context, ref Reference) (RolloutStatus, error) {
+	sts, err := s.client.AppsV1().StatefulSets(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return RolloutStatus{}, fmt.Errorf("error getting statefulset %s: %v", ref, err)
+	}
+	desired := int32(1)
+	if sts.Spec.Replicas != nil {
+		desired = *sts.Spec.Replicas
+	}
+	return RolloutStatus{
+		ObservedGeneration: sts.Status.ObservedGeneration,
+		DesiredReplicas:    desired,
+		ReadyReplicas:      sts.Status.ReadyReplicas,
+	}, nil
+}